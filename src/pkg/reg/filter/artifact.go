@@ -26,52 +26,190 @@ import (
 
 // DoFilterArtifacts filter the artifacts according to the filters
 func DoFilterArtifacts(artifacts []*model.Artifact, filters []*model.Filter) ([]*model.Artifact, error) {
-	fl, err := BuildArtifactFilters(filters)
+	plan, err := BuildArtifactFilters(filters)
 	if err != nil {
 		return nil, err
 	}
-	return fl.Filter(artifacts)
+	return plan.Filter(artifacts)
 }
 
-// BuildArtifactFilters from the defined filters
-func BuildArtifactFilters(filters []*model.Filter) (ArtifactFilters, error) {
-	var fs ArtifactFilters
+// regexCache compiles each distinct tagRegex and labelRegex pattern
+// encountered while building a FilterPlan exactly once, so multiple filters
+// referencing the same pattern (or the same filter re-checking it per
+// artifact) share one compiled *regexp.Regexp. The expression and labelKV
+// filters compile their own regexes and do not use this cache.
+type regexCache struct {
+	compiled map[string]*regexp.Regexp
+}
+
+func newRegexCache() *regexCache {
+	return &regexCache{compiled: map[string]*regexp.Regexp{}}
+}
+
+func (c *regexCache) compile(pattern string) (*regexp.Regexp, error) {
+	if re, ok := c.compiled[pattern]; ok {
+		return re, nil
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("invalid regex pattern %q: %v", pattern, err)
+	}
+	c.compiled[pattern] = re
+	return re, nil
+}
+
+// FilterPlan is the validated, pre-compiled result of BuildArtifactFilters.
+// Every regex and glob referenced by its filters is checked and compiled
+// once at build time, so a bad pattern fails the replication policy
+// immediately rather than partway through a run.
+type FilterPlan struct {
+	filters      ArtifactFilters
+	descriptions []string
+}
+
+// Filter runs the artifacts through every filter in the plan, in order.
+func (p *FilterPlan) Filter(artifacts []*model.Artifact) ([]*model.Artifact, error) {
+	return p.filters.Filter(artifacts)
+}
+
+// Explain returns a human-readable description of the filter chain, e.g.
+// for logging a replication policy's effective behavior during debugging.
+func (p *FilterPlan) Explain() string {
+	if len(p.descriptions) == 0 {
+		return "no filters configured"
+	}
+	return strings.Join(p.descriptions, " -> ")
+}
+
+// BuildArtifactFilters builds and validates a FilterPlan from the defined
+// filters. Regexes and globs are validated and compiled here so that a bad
+// pattern is reported immediately instead of during execution.
+func BuildArtifactFilters(filters []*model.Filter) (*FilterPlan, error) {
+	cache := newRegexCache()
+	plan := &FilterPlan{}
+
+	//Following block builds the plan's filters based on the type of filter
+	//Filter types are the ones shown on UI, like Tag, Label
+	//It builds for filters using double star and regex
 	for _, filter := range filters {
 		var f ArtifactFilter
+		var description string
 
-		//Following block builds ArtifactFilters based on the type of filter
-		//Filter types are the ones shown on UI, like Tag, Label
-		//It builds for filters using double star and regex
 		switch filter.Type {
 		case model.FilterTypeLabel:
 			f = &artifactLabelFilter{
 				labels:     filter.Value.([]string),
 				decoration: filter.Decoration,
 			}
+			description = fmt.Sprintf("label %s %v", filter.Decoration, filter.Value)
+
 		case model.FilterTypeTag:
+			pattern := filter.Value.(string)
+			if _, err := util.Match(pattern, ""); err != nil {
+				return nil, fmt.Errorf("invalid tag pattern %q: %v", pattern, err)
+			}
 			f = &artifactTagFilter{
-				pattern:    filter.Value.(string),
+				pattern:    pattern,
 				decoration: filter.Decoration,
 			}
+			description = fmt.Sprintf("tag %s %q", filter.Decoration, pattern)
 
 		case model.FilterTypeTagRegex:
+			pattern := filter.Value.(string)
+			re, err := cache.compile(pattern)
+			if err != nil {
+				return nil, err
+			}
 			f = &artifactTagFilterRegex{
-				pattern:    filter.Value.(string),
+				pattern:    pattern,
+				re:         re,
 				decoration: filter.Decoration,
 			}
+			description = fmt.Sprintf("tag~ %s %q", filter.Decoration, pattern)
 
 		case model.FilterTypeLabelRegex:
+			labels := filter.Value.([]string)
+			res := make([]*regexp.Regexp, 0, len(labels))
+			for _, label := range labels {
+				re, err := cache.compile(label)
+				if err != nil {
+					return nil, err
+				}
+				res = append(res, re)
+			}
 			f = &artifactLabelFilterRegex{
-				labels:     filter.Value.([]string),
+				labels:     labels,
+				res:        res,
+				decoration: filter.Decoration,
+			}
+			description = fmt.Sprintf("label~ %s %v", filter.Decoration, labels)
+
+		case model.FilterTypeExpression:
+			expr, ok := filter.Value.(string)
+			if !ok {
+				return nil, fmt.Errorf("filter value for type %q must be a string", model.FilterTypeExpression)
+			}
+			ef, err := Compile(expr)
+			if err != nil {
+				return nil, err
+			}
+			f = ef
+			description = fmt.Sprintf("expression %q", expr)
+
+		case model.FilterTypeLabelKV:
+			values, ok := filter.Value.([]string)
+			if !ok {
+				return nil, fmt.Errorf("filter value for type %q must be a []string", model.FilterTypeLabelKV)
+			}
+			var exprs []*labelKVExpr
+			for _, v := range values {
+				e, err := parseLabelKVExpr(v)
+				if err != nil {
+					return nil, err
+				}
+				exprs = append(exprs, e)
+			}
+			f = &artifactLabelKVFilter{
+				exprs:      exprs,
 				decoration: filter.Decoration,
 			}
+			description = fmt.Sprintf("labelKV %s %v", filter.Decoration, values)
+
+		case model.FilterTypeCEL:
+			expr, ok := filter.Value.(string)
+			if !ok {
+				return nil, fmt.Errorf("filter value for type %q must be a string", model.FilterTypeCEL)
+			}
+			cf, err := newArtifactCELFilter(expr)
+			if err != nil {
+				return nil, err
+			}
+			f = cf
+			description = fmt.Sprintf("cel %q", expr)
+
+		case model.FilterTypeSize:
+			sf, err := newArtifactSizeFilter(filter.Decoration, filter.Value)
+			if err != nil {
+				return nil, err
+			}
+			f = sf
+			description = fmt.Sprintf("size %s %v", filter.Decoration, filter.Value)
+
+		case model.FilterTypePushTime:
+			tf, err := newArtifactPushTimeFilter(filter.Decoration, filter.Value)
+			if err != nil {
+				return nil, err
+			}
+			f = tf
+			description = fmt.Sprintf("push_time %s %v", filter.Decoration, filter.Value)
 
 		}
 		if f != nil {
-			fs = append(fs, f)
+			plan.filters = append(plan.filters, f)
+			plan.descriptions = append(plan.descriptions, description)
 		}
 	}
-	return fs, nil
+	return plan, nil
 }
 
 // ArtifactFilter filter the artifacts
@@ -249,8 +387,9 @@ func (a *artifactTagFilter) Filter(artifacts []*model.Artifact) ([]*model.Artifa
 }
 
 type artifactTagFilterRegex struct {
-	//regex pattern
+	//regex pattern re was compiled from; an empty pattern passes every artifact
 	pattern string
+	re      *regexp.Regexp
 	// "matches", "excludes"
 	decoration string
 }
@@ -260,11 +399,7 @@ func (a *artifactTagFilterRegex) Filter(artifacts []*model.Artifact) ([]*model.A
 		return artifacts, nil
 	}
 
-	//Compiling regex & checking if its valid
-	filterRegexPattern, err := regexp.Compile(a.pattern)
-	if err != nil {
-		return nil, err
-	}
+	filterRegexPattern := a.re
 
 	var result []*model.Artifact
 	for _, artifact := range artifacts {
@@ -279,16 +414,6 @@ func (a *artifactTagFilterRegex) Filter(artifacts []*model.Artifact) ([]*model.A
 
 		// untagged artifact
 		if len(tagsForMatching) == 0 {
-
-			//The following comment block is filtering using double star
-			//Its kept for self reference
-			//TODO: Remove the following comment block
-
-			// match, err := util.Match(a.pattern, "")
-			// if err != nil {
-			// 	return nil, err
-			// }
-
 			//Filter matching using regex
 			match := filterRegexPattern.MatchString("")
 
@@ -307,29 +432,9 @@ func (a *artifactTagFilterRegex) Filter(artifacts []*model.Artifact) ([]*model.A
 		// tagged artifact
 		var tags []string
 		for _, tag := range tagsForMatching {
-
-			//The following comment block is filtering using double star
-			//Its kept for self reference
-			//TODO: Remove the following comment block
-
-			// match, err := util.Match(a.pattern, tag)
-			// if err != nil {
-			// 	return nil, err
-			// }
-
-			//The print statements are for debugging
-			//TODO: Remove following print statements
-
-			fmt.Println("PATTERN: ", a.pattern)
-			fmt.Println("TAG: ", tag)
-
 			//Filter matching using regex
 			match := filterRegexPattern.MatchString(tag)
 
-			fmt.Print("MATCH: ")
-			fmt.Println(match)
-			fmt.Println("")
-
 			if a.decoration == model.Excludes {
 				if !match {
 					tags = append(tags, tag)
@@ -365,6 +470,9 @@ func (a *artifactTagFilterRegex) Filter(artifacts []*model.Artifact) ([]*model.A
 
 type artifactLabelFilterRegex struct {
 	labels []string
+	// res[i] is the compiled pattern for labels[i], compiled once when the
+	// FilterPlan is built rather than per artifact
+	res []*regexp.Regexp
 	// "matches", "excludes"
 	decoration string
 }
@@ -376,45 +484,22 @@ func (a *artifactLabelFilterRegex) Filter(artifacts []*model.Artifact) ([]*model
 	}
 	var result []*model.Artifact
 	for _, artifact := range artifacts {
-		// labels := map[string]struct{}{}
-		// for _, label := range artifact.Labels {
-		// 	labels[label] = struct{}{}
-		// }
 		match := true
-	outer:
-		for _, label := range a.labels {
-			// if _, exist := labels[label]; !exist {
-			// 	match = false
-			// 	break
-			// }
-
-			filterRegexPattern, err := regexp.Compile(label)
-			if err != nil {
-				return nil, err
-			}
-
-			for i, lbl := range artifact.Labels {
-
-				fmt.Println("ARTIFACT LABEL")
-				fmt.Println(lbl)
-				fmt.Println("FILTER LABEL")
-				fmt.Println(label)
-				fmt.Print("\n")
-
-				exists := filterRegexPattern.MatchString(lbl)
-
-				fmt.Println("DIGEST")
-				fmt.Println(artifacts[i].Digest)
-				fmt.Println("MATCHES")
-				fmt.Println(exists)
-				fmt.Print("\n")
-
-				if !exists {
-					match = false
-					break outer
+		for _, filterRegexPattern := range a.res {
+			// the artifact satisfies this filter label if *any* of its
+			// labels matches the regex, not all of them
+			found := false
+			for _, lbl := range artifact.Labels {
+				if filterRegexPattern.MatchString(lbl) {
+					found = true
+					break
 				}
 			}
 
+			if !found {
+				match = false
+				break
+			}
 		}
 		// add the artifact to the result list if it contains all labels defined for the filter
 		if a.decoration == model.Excludes {
@@ -0,0 +1,71 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package filter
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/goharbor/harbor/src/pkg/reg/model"
+)
+
+func TestBuildArtifactFiltersRejectsInvalidRegexAtBuildTime(t *testing.T) {
+	_, err := BuildArtifactFilters([]*model.Filter{
+		{Type: model.FilterTypeTagRegex, Value: "(", Decoration: model.Matches},
+	})
+	assert.Error(t, err)
+}
+
+func TestRegexCacheReusesCompiledPattern(t *testing.T) {
+	cache := newRegexCache()
+	re1, err := cache.compile("^v\\d+$")
+	require.NoError(t, err)
+	re2, err := cache.compile("^v\\d+$")
+	require.NoError(t, err)
+	assert.Same(t, re1, re2, "identical patterns across filters in a plan should share one compiled regex")
+}
+
+func TestFilterPlanExplain(t *testing.T) {
+	plan, err := BuildArtifactFilters([]*model.Filter{
+		{Type: model.FilterTypeTag, Value: "v*", Decoration: model.Matches},
+		{Type: model.FilterTypeLabel, Value: []string{"release"}, Decoration: model.Matches},
+	})
+	require.NoError(t, err)
+	explanation := plan.Explain()
+	assert.Contains(t, explanation, "tag")
+	assert.Contains(t, explanation, "label")
+
+	empty, err := BuildArtifactFilters(nil)
+	require.NoError(t, err)
+	assert.Equal(t, "no filters configured", empty.Explain())
+}
+
+func TestArtifactLabelFilterRegexMatchesIfAnyLabelMatches(t *testing.T) {
+	plan, err := BuildArtifactFilters([]*model.Filter{
+		{Type: model.FilterTypeLabelRegex, Value: []string{"^stable$"}, Decoration: model.Matches},
+	})
+	require.NoError(t, err)
+
+	artifacts := []*model.Artifact{
+		{Digest: "multi-label", Labels: []string{"beta", "stable"}},
+		{Digest: "no-match", Labels: []string{"beta"}},
+	}
+	result, err := plan.Filter(artifacts)
+	require.NoError(t, err)
+	require.Len(t, result, 1)
+	assert.Equal(t, "multi-label", result[0].Digest)
+}
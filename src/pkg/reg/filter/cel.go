@@ -0,0 +1,103 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package filter
+
+import (
+	"fmt"
+
+	"github.com/google/cel-go/cel"
+
+	"github.com/goharbor/harbor/src/pkg/reg/model"
+)
+
+// celEnv declares the `artifact` variable exposed to CEL filter
+// expressions, with its fields kept dynamically typed so new metadata can
+// be surfaced through artifactToCELActivation without growing the
+// declaration list.
+var celEnv = mustNewCELEnv()
+
+func mustNewCELEnv() *cel.Env {
+	env, err := cel.NewEnv(
+		cel.Variable("artifact", cel.MapType(cel.StringType, cel.DynType)),
+	)
+	if err != nil {
+		// only possible if the fixed declaration above is malformed, which
+		// would be a programming error caught immediately by any caller
+		panic(fmt.Sprintf("filter: failed to create CEL environment: %v", err))
+	}
+	return env
+}
+
+// artifactCELFilter evaluates a pre-compiled CEL program such as
+// `artifact.size < 500*1024*1024 && artifact.tags.exists(t, t.startsWith("v")) && !artifact.labels.exists(l, l == "eol")`
+// against each artifact. Every field is accessed through the `artifact`
+// variable declared in celEnv; there are no bare top-level variables.
+type artifactCELFilter struct {
+	expr    string
+	program cel.Program
+}
+
+// newArtifactCELFilter compiles expr once so BuildArtifactFilters can
+// return a parse error immediately instead of failing per artifact.
+func newArtifactCELFilter(expr string) (*artifactCELFilter, error) {
+	ast, issues := celEnv.Compile(expr)
+	if issues != nil && issues.Err() != nil {
+		return nil, fmt.Errorf("invalid CEL filter expression %q: %v", expr, issues.Err())
+	}
+	if outType := ast.OutputType(); outType != cel.BoolType {
+		return nil, fmt.Errorf("CEL filter expression %q must evaluate to a bool, got %s", expr, outType)
+	}
+	program, err := celEnv.Program(ast)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build CEL program for expression %q: %v", expr, err)
+	}
+	return &artifactCELFilter{expr: expr, program: program}, nil
+}
+
+func (a *artifactCELFilter) Filter(artifacts []*model.Artifact) ([]*model.Artifact, error) {
+	var result []*model.Artifact
+	for _, artifact := range artifacts {
+		out, _, err := a.program.Eval(map[string]interface{}{
+			"artifact": artifactToCELActivation(artifact),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to evaluate CEL filter expression %q: %v", a.expr, err)
+		}
+		match, ok := out.Value().(bool)
+		if !ok {
+			return nil, fmt.Errorf("CEL filter expression %q did not evaluate to a bool", a.expr)
+		}
+		if match {
+			result = append(result, artifact)
+		}
+	}
+	return result, nil
+}
+
+// artifactToCELActivation exposes the artifact fields CEL filter
+// expressions can reference. Add new fields here rather than a new
+// FilterType enum value whenever more artifact metadata needs filtering.
+func artifactToCELActivation(artifact *model.Artifact) map[string]interface{} {
+	return map[string]interface{}{
+		"type":         artifact.Type,
+		"digest":       artifact.Digest,
+		"tags":         artifact.Tags,
+		"labels":       artifact.Labels,
+		"push_time":    artifact.PushTime,
+		"size":         artifact.Size,
+		"is_accessory": artifact.IsAcc,
+		"parent_tags":  artifact.ParentTags,
+	}
+}
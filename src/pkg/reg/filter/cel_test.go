@@ -0,0 +1,72 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package filter
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/goharbor/harbor/src/pkg/reg/model"
+)
+
+func TestNewArtifactCELFilterInvalidExpression(t *testing.T) {
+	_, err := newArtifactCELFilter("artifact.size <")
+	assert.Error(t, err)
+
+	// a well-formed but non-bool expression should also be rejected at
+	// build time rather than failing per artifact
+	_, err = newArtifactCELFilter("artifact.size")
+	assert.Error(t, err)
+}
+
+func TestArtifactCELFilter(t *testing.T) {
+	f, err := newArtifactCELFilter(
+		`artifact.size < 500*1024*1024 && artifact.tags.exists(t, t.startsWith("v")) && !artifact.labels.exists(l, l == "eol")`)
+	require.NoError(t, err)
+
+	artifacts := []*model.Artifact{
+		{
+			Digest:   "small-release",
+			Size:     100 * 1024 * 1024,
+			Tags:     []string{"v1.0.0"},
+			Labels:   []string{"release"},
+			PushTime: time.Unix(0, 0),
+		},
+		{
+			Digest: "too-big",
+			Size:   600 * 1024 * 1024,
+			Tags:   []string{"v1.0.0"},
+		},
+		{
+			Digest: "eol",
+			Size:   10 * 1024 * 1024,
+			Tags:   []string{"v1.0.0"},
+			Labels: []string{"eol"},
+		},
+		{
+			Digest: "no-v-tag",
+			Size:   10 * 1024 * 1024,
+			Tags:   []string{"latest"},
+		},
+	}
+
+	result, err := f.Filter(artifacts)
+	require.NoError(t, err)
+	require.Len(t, result, 1)
+	assert.Equal(t, "small-release", result[0].Digest)
+}
@@ -0,0 +1,404 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package filter
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/goharbor/harbor/src/pkg/reg/model"
+	"github.com/goharbor/harbor/src/pkg/reg/util"
+)
+
+// exprNode is a node of the boolean filter expression AST. Leaves evaluate a
+// single artifact against one of the existing filter primitives (tag, tag
+// regex, label, tagged, type); internal nodes combine the result of their
+// children with AND/OR/NOT.
+type exprNode interface {
+	match(artifact *model.Artifact) (bool, error)
+}
+
+type andNode struct {
+	left, right exprNode
+}
+
+func (n *andNode) match(artifact *model.Artifact) (bool, error) {
+	ok, err := n.left.match(artifact)
+	if err != nil || !ok {
+		return false, err
+	}
+	return n.right.match(artifact)
+}
+
+type orNode struct {
+	left, right exprNode
+}
+
+func (n *orNode) match(artifact *model.Artifact) (bool, error) {
+	ok, err := n.left.match(artifact)
+	if err != nil || ok {
+		return ok, err
+	}
+	return n.right.match(artifact)
+}
+
+type notNode struct {
+	child exprNode
+}
+
+func (n *notNode) match(artifact *model.Artifact) (bool, error) {
+	ok, err := n.child.match(artifact)
+	if err != nil {
+		return false, err
+	}
+	return !ok, nil
+}
+
+// tagLeaf matches artifacts whose tags (or parent tags for accessories)
+// contain a tag matching the double-star pattern.
+type tagLeaf struct {
+	pattern string
+}
+
+func (n *tagLeaf) match(artifact *model.Artifact) (bool, error) {
+	for _, tag := range tagsForMatching(artifact) {
+		ok, err := util.Match(n.pattern, tag)
+		if err != nil {
+			return false, err
+		}
+		if ok {
+			return true, nil
+		}
+	}
+	if len(tagsForMatching(artifact)) == 0 {
+		return util.Match(n.pattern, "")
+	}
+	return false, nil
+}
+
+// tagRegexLeaf matches artifacts whose tags (or parent tags for accessories)
+// contain a tag matching the pre-compiled regex.
+type tagRegexLeaf struct {
+	re *regexp.Regexp
+}
+
+func (n *tagRegexLeaf) match(artifact *model.Artifact) (bool, error) {
+	tags := tagsForMatching(artifact)
+	if len(tags) == 0 {
+		return n.re.MatchString(""), nil
+	}
+	for _, tag := range tags {
+		if n.re.MatchString(tag) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// labelLeaf matches artifacts carrying the given label.
+type labelLeaf struct {
+	label string
+}
+
+func (n *labelLeaf) match(artifact *model.Artifact) (bool, error) {
+	for _, label := range artifact.Labels {
+		if label == n.label {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// taggedLeaf matches tagged (or untagged) artifacts.
+type taggedLeaf struct {
+	tagged bool
+}
+
+func (n *taggedLeaf) match(artifact *model.Artifact) (bool, error) {
+	return n.tagged == (len(artifact.Tags) > 0), nil
+}
+
+// typeLeaf matches artifacts of the given type, e.g. "image", "chart".
+type typeLeaf struct {
+	artifactType string
+}
+
+func (n *typeLeaf) match(artifact *model.Artifact) (bool, error) {
+	return strings.EqualFold(artifact.Type, n.artifactType), nil
+}
+
+func tagsForMatching(artifact *model.Artifact) []string {
+	if artifact.IsAcc {
+		return artifact.ParentTags
+	}
+	return artifact.Tags
+}
+
+// expressionFilter evaluates a pre-compiled boolean expression AST against
+// each artifact, walking the tree once per artifact.
+type expressionFilter struct {
+	root exprNode
+}
+
+func (a *expressionFilter) Filter(artifacts []*model.Artifact) ([]*model.Artifact, error) {
+	var result []*model.Artifact
+	for _, artifact := range artifacts {
+		ok, err := a.root.match(artifact)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			result = append(result, artifact)
+		}
+	}
+	return result, nil
+}
+
+// Compile parses a boolean filter expression such as
+// `(tag~"^v\d+\.\d+\.\d+$" OR label="release") AND NOT label="deprecated"`
+// into an ArtifactFilter. All regexes referenced by the expression are
+// compiled once, at parse time, so the hot loop only evaluates.
+func Compile(expr string) (ArtifactFilter, error) {
+	p := &exprParser{tokens: tokenize(expr)}
+	root, err := p.parseExpr()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos != len(p.tokens) {
+		return nil, fmt.Errorf("unexpected token %q in filter expression", p.tokens[p.pos].value)
+	}
+	return &expressionFilter{root: root}, nil
+}
+
+// tokenKind identifies the lexical class of a token produced by tokenize.
+type tokenKind int
+
+const (
+	tokenIdent tokenKind = iota
+	tokenString
+	tokenOp
+	tokenLParen
+	tokenRParen
+	tokenAnd
+	tokenOr
+	tokenNot
+)
+
+type token struct {
+	kind  tokenKind
+	value string
+}
+
+// tokenize splits a filter expression into tokens: identifiers (tag, label,
+// type, tagged), operators (= ~), quoted strings, parentheses, and the
+// keywords AND/OR/NOT.
+func tokenize(expr string) []token {
+	var tokens []token
+	runes := []rune(expr)
+	for i := 0; i < len(runes); {
+		c := runes[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n':
+			i++
+		case c == '(':
+			tokens = append(tokens, token{kind: tokenLParen, value: "("})
+			i++
+		case c == ')':
+			tokens = append(tokens, token{kind: tokenRParen, value: ")"})
+			i++
+		case c == '=' || c == '~':
+			tokens = append(tokens, token{kind: tokenOp, value: string(c)})
+			i++
+		case c == '"':
+			j := i + 1
+			for j < len(runes) && runes[j] != '"' {
+				if runes[j] == '\\' && j+1 < len(runes) {
+					j++
+				}
+				j++
+			}
+			tokens = append(tokens, token{kind: tokenString, value: string(runes[i+1 : j])})
+			i = j + 1
+		default:
+			j := i
+			for j < len(runes) && isIdentRune(runes[j]) {
+				j++
+			}
+			word := string(runes[i:j])
+			switch strings.ToUpper(word) {
+			case "AND":
+				tokens = append(tokens, token{kind: tokenAnd, value: word})
+			case "OR":
+				tokens = append(tokens, token{kind: tokenOr, value: word})
+			case "NOT":
+				tokens = append(tokens, token{kind: tokenNot, value: word})
+			default:
+				tokens = append(tokens, token{kind: tokenIdent, value: word})
+			}
+			i = j
+		}
+	}
+	return tokens
+}
+
+func isIdentRune(r rune) bool {
+	return r != ' ' && r != '\t' && r != '\n' && r != '(' && r != ')' && r != '=' && r != '~' && r != '"'
+}
+
+// exprParser is a recursive-descent parser over the precedence chain
+// orExpr -> andExpr -> notExpr -> primary.
+type exprParser struct {
+	tokens []token
+	pos    int
+}
+
+func (p *exprParser) peek() (token, bool) {
+	if p.pos >= len(p.tokens) {
+		return token{}, false
+	}
+	return p.tokens[p.pos], true
+}
+
+func (p *exprParser) parseExpr() (exprNode, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		t, ok := p.peek()
+		if !ok || t.kind != tokenOr {
+			return left, nil
+		}
+		p.pos++
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = &orNode{left: left, right: right}
+	}
+}
+
+func (p *exprParser) parseAnd() (exprNode, error) {
+	left, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		t, ok := p.peek()
+		if !ok || t.kind != tokenAnd {
+			return left, nil
+		}
+		p.pos++
+		right, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		left = &andNode{left: left, right: right}
+	}
+}
+
+func (p *exprParser) parseNot() (exprNode, error) {
+	if t, ok := p.peek(); ok && t.kind == tokenNot {
+		p.pos++
+		child, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		return &notNode{child: child}, nil
+	}
+	return p.parsePrimary()
+}
+
+func (p *exprParser) parsePrimary() (exprNode, error) {
+	t, ok := p.peek()
+	if !ok {
+		return nil, fmt.Errorf("unexpected end of filter expression")
+	}
+	if t.kind == tokenLParen {
+		p.pos++
+		node, err := p.parseExpr()
+		if err != nil {
+			return nil, err
+		}
+		closing, ok := p.peek()
+		if !ok || closing.kind != tokenRParen {
+			return nil, fmt.Errorf("missing closing parenthesis in filter expression")
+		}
+		p.pos++
+		return node, nil
+	}
+	return p.parseLeaf()
+}
+
+func (p *exprParser) parseLeaf() (exprNode, error) {
+	t, ok := p.peek()
+	if !ok || t.kind != tokenIdent {
+		return nil, fmt.Errorf("expected a filter leaf (tag, tag~, label, tagged, type) in expression")
+	}
+	p.pos++
+	switch strings.ToLower(t.value) {
+	case "tagged":
+		return &taggedLeaf{tagged: true}, nil
+	case "untagged":
+		return &taggedLeaf{tagged: false}, nil
+	case "tag":
+		op, str, err := p.parseOpAndString()
+		if err != nil {
+			return nil, err
+		}
+		if op == "~" {
+			re, err := regexp.Compile(str)
+			if err != nil {
+				return nil, fmt.Errorf("invalid regex in tag~ leaf: %v", err)
+			}
+			return &tagRegexLeaf{re: re}, nil
+		}
+		// validate the glob now so a malformed pattern fails Compile
+		// instead of the first time match runs it against an artifact
+		if _, err := util.Match(str, ""); err != nil {
+			return nil, fmt.Errorf("invalid glob in tag= leaf: %v", err)
+		}
+		return &tagLeaf{pattern: str}, nil
+	case "label":
+		_, str, err := p.parseOpAndString()
+		if err != nil {
+			return nil, err
+		}
+		return &labelLeaf{label: str}, nil
+	case "type":
+		_, str, err := p.parseOpAndString()
+		if err != nil {
+			return nil, err
+		}
+		return &typeLeaf{artifactType: str}, nil
+	default:
+		return nil, fmt.Errorf("unknown filter leaf %q in expression", t.value)
+	}
+}
+
+func (p *exprParser) parseOpAndString() (op string, value string, err error) {
+	opToken, ok := p.peek()
+	if !ok || opToken.kind != tokenOp {
+		return "", "", fmt.Errorf("expected '=' or '~' after filter leaf")
+	}
+	p.pos++
+	strToken, ok := p.peek()
+	if !ok || strToken.kind != tokenString {
+		return "", "", fmt.Errorf("expected a quoted string after %q", opToken.value)
+	}
+	p.pos++
+	return opToken.value, strToken.value, nil
+}
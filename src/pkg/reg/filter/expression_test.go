@@ -0,0 +1,85 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package filter
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/goharbor/harbor/src/pkg/reg/model"
+)
+
+func TestCompileAndFilterExpression(t *testing.T) {
+	artifacts := []*model.Artifact{
+		{Digest: "release-tag", Tags: []string{"v1.2.3"}, Labels: []string{"release"}},
+		{Digest: "rc-tag", Tags: []string{"v1.2.3-rc1"}, Labels: []string{"deprecated"}},
+		{Digest: "no-labels", Tags: []string{"v9.9.9"}},
+	}
+
+	f, err := Compile(`(tag~"^v\d+\.\d+\.\d+$" OR label="release") AND NOT label="deprecated"`)
+	require.NoError(t, err)
+
+	result, err := f.Filter(artifacts)
+	require.NoError(t, err)
+
+	var digests []string
+	for _, a := range result {
+		digests = append(digests, a.Digest)
+	}
+	assert.ElementsMatch(t, []string{"release-tag", "no-labels"}, digests)
+}
+
+func TestCompileInvalidExpression(t *testing.T) {
+	_, err := Compile(`tag~"("`)
+	assert.Error(t, err)
+
+	_, err = Compile(`tag="v1" AND`)
+	assert.Error(t, err)
+
+	_, err = Compile(`unknownLeaf="x"`)
+	assert.Error(t, err)
+}
+
+func TestExpressionLeaves(t *testing.T) {
+	taggedArtifact := &model.Artifact{Tags: []string{"latest"}}
+	untaggedArtifact := &model.Artifact{}
+	accArtifact := &model.Artifact{IsAcc: true, ParentTags: []string{"v2.0.0"}}
+
+	cases := []struct {
+		name     string
+		expr     string
+		artifact *model.Artifact
+		want     bool
+	}{
+		{"tag glob matches", `tag="latest"`, taggedArtifact, true},
+		{"tag glob excludes", `NOT tag="latest"`, taggedArtifact, false},
+		{"tagged leaf true", "tagged", taggedArtifact, true},
+		{"tagged leaf false", "tagged", untaggedArtifact, false},
+		{"type leaf case-insensitive", `type="IMAGE"`, &model.Artifact{Type: "image"}, true},
+		{"accessory matches parent tags", `tag~"^v2"`, accArtifact, true},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			f, err := Compile(c.expr)
+			require.NoError(t, err)
+			result, err := f.Filter([]*model.Artifact{c.artifact})
+			require.NoError(t, err)
+			assert.Equal(t, c.want, len(result) == 1)
+		})
+	}
+}
@@ -0,0 +1,201 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package filter
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/goharbor/harbor/src/pkg/reg/model"
+	"github.com/goharbor/harbor/src/pkg/reg/util"
+)
+
+// labelKVOp is the comparison operator used in a `key[=value]` label
+// expression.
+type labelKVOp int
+
+const (
+	labelKVOpExists labelKVOp = iota // bare "key", no operator
+	labelKVOpEquals
+	labelKVOpNotEquals
+	labelKVOpRegexMatch
+	labelKVOpRegexNotMatch
+)
+
+// labelKVExpr is a single parsed `key[=value]` filter expression, e.g.
+// `env!=staging` or `team=~^platform-.*$`. The value is taken verbatim;
+// quotes are not stripped.
+type labelKVExpr struct {
+	key   string
+	op    labelKVOp
+	value string
+	re    *regexp.Regexp // set when op is labelKVOpRegexMatch/RegexNotMatch
+}
+
+// parseLabelKVExpr parses a filter value of the form `key`, `key=value`,
+// `key!=value`, `key=~value` or `key!~value`. The value side of `=`/`!=`
+// may contain `*`/`?` globs, matched with the same double-star matcher
+// used by the tag filters.
+func parseLabelKVExpr(expr string) (*labelKVExpr, error) {
+	candidates := []struct {
+		sep string
+		op  labelKVOp
+	}{
+		{"!=", labelKVOpNotEquals},
+		{"=~", labelKVOpRegexMatch},
+		{"!~", labelKVOpRegexNotMatch},
+		{"=", labelKVOpEquals},
+	}
+
+	// pick whichever separator occurs earliest in expr, not whichever
+	// candidate is tried first; "=" and "=~" can start at the same index
+	// (e.g. "a=~x"), in which case the longer, more specific "=~" wins
+	bestIdx := -1
+	var best struct {
+		sep string
+		op  labelKVOp
+	}
+	for _, candidate := range candidates {
+		idx := strings.Index(expr, candidate.sep)
+		if idx < 0 {
+			continue
+		}
+		if bestIdx == -1 || idx < bestIdx || (idx == bestIdx && len(candidate.sep) > len(best.sep)) {
+			bestIdx = idx
+			best = candidate
+		}
+	}
+	if bestIdx == -1 {
+		return &labelKVExpr{key: expr, op: labelKVOpExists}, nil
+	}
+
+	key := expr[:bestIdx]
+	value := expr[bestIdx+len(best.sep):]
+	e := &labelKVExpr{key: key, op: best.op, value: value}
+	switch best.op {
+	case labelKVOpRegexMatch, labelKVOpRegexNotMatch:
+		re, err := regexp.Compile(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid regex in label filter %q: %v", expr, err)
+		}
+		e.re = re
+	case labelKVOpEquals, labelKVOpNotEquals:
+		// validate the glob now so a malformed pattern fails the
+		// replication policy at build time, not the first time
+		// matchesArtifact runs it against a real artifact
+		if _, err := util.Match(value, ""); err != nil {
+			return nil, fmt.Errorf("invalid glob in label filter %q: %v", expr, err)
+		}
+	}
+	return e, nil
+}
+
+// splitLabelKV splits a raw artifact label into a key/value pair on the
+// first "=". Labels without "=" are treated as a bare key with an empty
+// value.
+func splitLabelKV(label string) (key, value string) {
+	if idx := strings.Index(label, "="); idx >= 0 {
+		return label[:idx], label[idx+1:]
+	}
+	return label, ""
+}
+
+func (e *labelKVExpr) matchesArtifact(artifact *model.Artifact) (bool, error) {
+	for _, label := range artifact.Labels {
+		key, value := splitLabelKV(label)
+		if key != e.key {
+			continue
+		}
+		switch e.op {
+		case labelKVOpExists:
+			return true, nil
+		case labelKVOpEquals:
+			ok, err := util.Match(e.value, value)
+			if err != nil {
+				return false, err
+			}
+			if ok {
+				return true, nil
+			}
+		case labelKVOpNotEquals:
+			// a same-key label equal to the excluded value disqualifies
+			// the artifact outright, even if another same-key label
+			// doesn't equal it
+			ok, err := util.Match(e.value, value)
+			if err != nil {
+				return false, err
+			}
+			if ok {
+				return false, nil
+			}
+		case labelKVOpRegexMatch:
+			if e.re.MatchString(value) {
+				return true, nil
+			}
+		case labelKVOpRegexNotMatch:
+			// same reasoning as labelKVOpNotEquals above
+			if e.re.MatchString(value) {
+				return false, nil
+			}
+		}
+	}
+	// for the negative operators, matching means no same-key label
+	// equaled/matched the value; for the positive operators, no same-key
+	// label ever matched
+	return e.op == labelKVOpNotEquals || e.op == labelKVOpRegexNotMatch, nil
+}
+
+// artifactLabelKVFilter filters artifacts on structured `key[=value]`
+// label expressions, matching the artifact's labels as key/value pairs
+// split on the first "=". An artifact matches the filter if every
+// expression in the filter matches it: a bare key, "=" or "=~" expression
+// needs at least one same-key label to match, while a "!=" or "!~"
+// expression needs no same-key label to match.
+type artifactLabelKVFilter struct {
+	exprs []*labelKVExpr
+	// "matches", "excludes"
+	decoration string
+}
+
+func (a *artifactLabelKVFilter) Filter(artifacts []*model.Artifact) ([]*model.Artifact, error) {
+	if len(a.exprs) == 0 {
+		return artifacts, nil
+	}
+	var result []*model.Artifact
+	for _, artifact := range artifacts {
+		match := true
+		for _, expr := range a.exprs {
+			ok, err := expr.matchesArtifact(artifact)
+			if err != nil {
+				return nil, err
+			}
+			if !ok {
+				match = false
+				break
+			}
+		}
+		if a.decoration == model.Excludes {
+			if !match {
+				result = append(result, artifact)
+			}
+		} else {
+			if match {
+				result = append(result, artifact)
+			}
+		}
+	}
+	return result, nil
+}
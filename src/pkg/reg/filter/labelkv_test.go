@@ -0,0 +1,97 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package filter
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/goharbor/harbor/src/pkg/reg/model"
+)
+
+func TestParseLabelKVExpr(t *testing.T) {
+	cases := []struct {
+		name      string
+		expr      string
+		wantKey   string
+		wantOp    labelKVOp
+		wantValue string
+	}{
+		{"bare key", "env", "env", labelKVOpExists, ""},
+		{"equals", "env=prod", "env", labelKVOpEquals, "prod"},
+		{"not equals", "env!=prod", "env", labelKVOpNotEquals, "prod"},
+		{"regex match", `team=~^platform-.*$`, "team", labelKVOpRegexMatch, `^platform-.*$`},
+		{"regex not match", "team!~eol.*", "team", labelKVOpRegexNotMatch, "eol.*"},
+		{"earliest separator wins over later one", "a=~x!=y", "a", labelKVOpRegexMatch, "x!=y"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			e, err := parseLabelKVExpr(c.expr)
+			require.NoError(t, err)
+			assert.Equal(t, c.wantKey, e.key)
+			assert.Equal(t, c.wantOp, e.op)
+			assert.Equal(t, c.wantValue, e.value)
+		})
+	}
+}
+
+func TestLabelKVFilterNotEqualsRequiresNoSameKeyMatch(t *testing.T) {
+	artifact := &model.Artifact{Labels: []string{"team=platform", "team=legacy"}}
+
+	matches, err := parseLabelKVExprAndMatch(t, "team=platform", artifact)
+	require.NoError(t, err)
+	assert.True(t, matches, "team=platform should match an artifact carrying that label")
+
+	matches, err = parseLabelKVExprAndMatch(t, "team!=platform", artifact)
+	require.NoError(t, err)
+	assert.False(t, matches, "team!=platform must not match an artifact that also carries team=platform")
+}
+
+func TestLabelKVFilterNotEqualsMatchesWhenNoSameKeyEquals(t *testing.T) {
+	artifact := &model.Artifact{Labels: []string{"team=legacy"}}
+
+	matches, err := parseLabelKVExprAndMatch(t, "team!=platform", artifact)
+	require.NoError(t, err)
+	assert.True(t, matches)
+}
+
+func parseLabelKVExprAndMatch(t *testing.T, expr string, artifact *model.Artifact) (bool, error) {
+	t.Helper()
+	e, err := parseLabelKVExpr(expr)
+	require.NoError(t, err)
+	return e.matchesArtifact(artifact)
+}
+
+func TestArtifactLabelKVFilter(t *testing.T) {
+	exprs := []*labelKVExpr{}
+	for _, v := range []string{"env=prod"} {
+		e, err := parseLabelKVExpr(v)
+		require.NoError(t, err)
+		exprs = append(exprs, e)
+	}
+	f := &artifactLabelKVFilter{exprs: exprs, decoration: model.Matches}
+
+	artifacts := []*model.Artifact{
+		{Digest: "prod", Labels: []string{"env=prod"}},
+		{Digest: "staging", Labels: []string{"env=staging"}},
+	}
+	result, err := f.Filter(artifacts)
+	require.NoError(t, err)
+	require.Len(t, result, 1)
+	assert.Equal(t, "prod", result[0].Digest)
+}
@@ -0,0 +1,180 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package filter
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/goharbor/harbor/src/pkg/reg/model"
+)
+
+// comparisonOp is the comparison requested via filter.Decoration for the
+// size and push-time filters: "gt", "lt", "gte", "lte" or "between".
+type comparisonOp string
+
+const (
+	comparisonGT      comparisonOp = "gt"
+	comparisonLT      comparisonOp = "lt"
+	comparisonGTE     comparisonOp = "gte"
+	comparisonLTE     comparisonOp = "lte"
+	comparisonBetween comparisonOp = "between"
+)
+
+func validateComparisonOp(op string) (comparisonOp, error) {
+	switch comparisonOp(op) {
+	case comparisonGT, comparisonLT, comparisonGTE, comparisonLTE, comparisonBetween:
+		return comparisonOp(op), nil
+	default:
+		return "", fmt.Errorf("unsupported comparison %q, must be one of gt, lt, gte, lte, between", op)
+	}
+}
+
+// artifactSizeFilter filters artifacts by their size in bytes, e.g. to skip
+// layers over 2GiB in a mirror job. When the source adapter couldn't
+// populate an artifact's size (Size <= 0), the artifact passes through
+// untouched rather than being dropped.
+type artifactSizeFilter struct {
+	op     comparisonOp
+	size   int64
+	sizeTo int64 // only set when op is comparisonBetween
+}
+
+func newArtifactSizeFilter(op string, value interface{}) (*artifactSizeFilter, error) {
+	cop, err := validateComparisonOp(op)
+	if err != nil {
+		return nil, err
+	}
+	f := &artifactSizeFilter{op: cop}
+	if cop == comparisonBetween {
+		bounds, ok := value.([]int64)
+		if !ok || len(bounds) != 2 {
+			return nil, fmt.Errorf("size filter with \"between\" comparison requires a []int64 value of length 2")
+		}
+		if bounds[0] > bounds[1] {
+			return nil, fmt.Errorf("size filter with \"between\" comparison requires the lower bound (%d) to be <= the upper bound (%d)", bounds[0], bounds[1])
+		}
+		f.size, f.sizeTo = bounds[0], bounds[1]
+		return f, nil
+	}
+	size, ok := value.(int64)
+	if !ok {
+		return nil, fmt.Errorf("size filter requires an int64 value")
+	}
+	f.size = size
+	return f, nil
+}
+
+func (a *artifactSizeFilter) Filter(artifacts []*model.Artifact) ([]*model.Artifact, error) {
+	var result []*model.Artifact
+	for _, artifact := range artifacts {
+		// the adapter couldn't supply the size for this artifact; let it
+		// through rather than dropping it for a comparison we can't make
+		if artifact.Size <= 0 {
+			result = append(result, artifact)
+			continue
+		}
+		if compareInt64(artifact.Size, a.op, a.size, a.sizeTo) {
+			result = append(result, artifact)
+		}
+	}
+	return result, nil
+}
+
+// artifactPushTimeFilter filters artifacts by their push time, e.g. to
+// time-window a mirror job to only artifacts pushed in the last 7 days.
+// When the source adapter couldn't populate an artifact's push time
+// (PushTime is zero), the artifact passes through untouched rather than
+// being dropped.
+type artifactPushTimeFilter struct {
+	op         comparisonOp
+	pushTime   time.Time
+	pushTimeTo time.Time // only set when op is comparisonBetween
+}
+
+func newArtifactPushTimeFilter(op string, value interface{}) (*artifactPushTimeFilter, error) {
+	cop, err := validateComparisonOp(op)
+	if err != nil {
+		return nil, err
+	}
+	f := &artifactPushTimeFilter{op: cop}
+	if cop == comparisonBetween {
+		bounds, ok := value.([]time.Time)
+		if !ok || len(bounds) != 2 {
+			return nil, fmt.Errorf("push-time filter with \"between\" comparison requires a []time.Time value of length 2")
+		}
+		if bounds[0].After(bounds[1]) {
+			return nil, fmt.Errorf("push-time filter with \"between\" comparison requires the lower bound (%s) to be <= the upper bound (%s)", bounds[0], bounds[1])
+		}
+		f.pushTime, f.pushTimeTo = bounds[0], bounds[1]
+		return f, nil
+	}
+	t, ok := value.(time.Time)
+	if !ok {
+		return nil, fmt.Errorf("push-time filter requires a time.Time value")
+	}
+	f.pushTime = t
+	return f, nil
+}
+
+func (a *artifactPushTimeFilter) Filter(artifacts []*model.Artifact) ([]*model.Artifact, error) {
+	var result []*model.Artifact
+	for _, artifact := range artifacts {
+		// the adapter couldn't supply the push time for this artifact; let
+		// it through rather than dropping it for a comparison we can't make
+		if artifact.PushTime.IsZero() {
+			result = append(result, artifact)
+			continue
+		}
+		if compareTime(artifact.PushTime, a.op, a.pushTime, a.pushTimeTo) {
+			result = append(result, artifact)
+		}
+	}
+	return result, nil
+}
+
+func compareInt64(value int64, op comparisonOp, threshold, thresholdTo int64) bool {
+	switch op {
+	case comparisonGT:
+		return value > threshold
+	case comparisonLT:
+		return value < threshold
+	case comparisonGTE:
+		return value >= threshold
+	case comparisonLTE:
+		return value <= threshold
+	case comparisonBetween:
+		return value >= threshold && value <= thresholdTo
+	default:
+		return false
+	}
+}
+
+func compareTime(value time.Time, op comparisonOp, threshold, thresholdTo time.Time) bool {
+	switch op {
+	case comparisonGT:
+		return value.After(threshold)
+	case comparisonLT:
+		return value.Before(threshold)
+	case comparisonGTE:
+		return value.After(threshold) || value.Equal(threshold)
+	case comparisonLTE:
+		return value.Before(threshold) || value.Equal(threshold)
+	case comparisonBetween:
+		return !value.Before(threshold) && !value.After(thresholdTo)
+	default:
+		return false
+	}
+}
@@ -0,0 +1,93 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package filter
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/goharbor/harbor/src/pkg/reg/model"
+)
+
+func TestArtifactSizeFilterGT(t *testing.T) {
+	f, err := newArtifactSizeFilter("gt", int64(2*1024*1024*1024))
+	require.NoError(t, err)
+
+	artifacts := []*model.Artifact{
+		{Digest: "small", Size: 1 * 1024 * 1024 * 1024},
+		{Digest: "huge", Size: 3 * 1024 * 1024 * 1024},
+		{Digest: "unknown-size", Size: 0},
+	}
+	result, err := f.Filter(artifacts)
+	require.NoError(t, err)
+
+	var digests []string
+	for _, a := range result {
+		digests = append(digests, a.Digest)
+	}
+	assert.ElementsMatch(t, []string{"huge", "unknown-size"}, digests,
+		"artifacts with an unreportable size should pass through rather than be dropped")
+}
+
+func TestArtifactSizeFilterBetweenValidatesBounds(t *testing.T) {
+	_, err := newArtifactSizeFilter("between", []int64{100, 10})
+	assert.Error(t, err, "a between filter with lower > upper bound should be rejected at build time")
+
+	f, err := newArtifactSizeFilter("between", []int64{10, 100})
+	require.NoError(t, err)
+	result, err := f.Filter([]*model.Artifact{{Digest: "in-range", Size: 50}, {Digest: "out", Size: 200}})
+	require.NoError(t, err)
+	require.Len(t, result, 1)
+	assert.Equal(t, "in-range", result[0].Digest)
+}
+
+func TestArtifactPushTimeFilterLTE(t *testing.T) {
+	cutoff := time.Date(2026, 7, 19, 0, 0, 0, 0, time.UTC)
+	f, err := newArtifactPushTimeFilter("lte", cutoff)
+	require.NoError(t, err)
+
+	artifacts := []*model.Artifact{
+		{Digest: "old", PushTime: cutoff.Add(-48 * time.Hour)},
+		{Digest: "new", PushTime: cutoff.Add(48 * time.Hour)},
+		{Digest: "unknown-time"},
+	}
+	result, err := f.Filter(artifacts)
+	require.NoError(t, err)
+
+	var digests []string
+	for _, a := range result {
+		digests = append(digests, a.Digest)
+	}
+	assert.ElementsMatch(t, []string{"old", "unknown-time"}, digests)
+}
+
+func TestNewArtifactPushTimeFilterBetweenValidatesBounds(t *testing.T) {
+	start := time.Date(2026, 7, 19, 0, 0, 0, 0, time.UTC)
+	end := start.Add(7 * 24 * time.Hour)
+
+	_, err := newArtifactPushTimeFilter("between", []time.Time{end, start})
+	assert.Error(t, err)
+
+	_, err = newArtifactPushTimeFilter("between", []time.Time{start, end})
+	assert.NoError(t, err)
+}
+
+func TestValidateComparisonOpRejectsUnknownOp(t *testing.T) {
+	_, err := validateComparisonOp("eq")
+	assert.Error(t, err)
+}
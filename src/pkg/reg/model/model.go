@@ -0,0 +1,84 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import "time"
+
+// FilterType represents the type of the filter
+type FilterType string
+
+const (
+	// FilterTypeLabel represents the filter type of label
+	FilterTypeLabel FilterType = "label"
+	// FilterTypeTag represents the filter type of tag
+	FilterTypeTag FilterType = "tag"
+	// FilterTypeTagRegex represents the filter type of regex-based tag
+	FilterTypeTagRegex FilterType = "tagRegex"
+	// FilterTypeLabelRegex represents the filter type of regex-based label
+	FilterTypeLabelRegex FilterType = "labelRegex"
+	// FilterTypeExpression represents the filter type of boolean expression
+	FilterTypeExpression FilterType = "expression"
+	// FilterTypeLabelKV represents the filter type of structured key[=value] label expressions
+	FilterTypeLabelKV FilterType = "labelKV"
+	// FilterTypeCEL represents the filter type of a CEL predicate evaluated against the artifact
+	FilterTypeCEL FilterType = "cel"
+	// FilterTypeSize represents the filter type of artifact size comparisons
+	FilterTypeSize FilterType = "size"
+	// FilterTypePushTime represents the filter type of artifact push-time comparisons
+	FilterTypePushTime FilterType = "pushTime"
+)
+
+const (
+	// Matches is one of the values of Filter.Decoration, to keep the artifacts matching the filter
+	Matches = "matches"
+	// Excludes is one of the values of Filter.Decoration, to drop the artifacts matching the filter
+	Excludes = "excludes"
+)
+
+// Filter holds the info of the filter
+type Filter struct {
+	Type FilterType `json:"type"`
+	// Value is the filter value, its type is decided by Type:
+	// label: []string, tag/tagRegex: string, labelRegex: []string,
+	// expression/labelKV/cel: string/[]string, size/pushTime: int64/time.Time
+	// or []int64/[]time.Time when Decoration is "between"
+	Value interface{} `json:"value"`
+	// Decoration is "matches"/"excludes" for most filter types, and one of
+	// "gt"/"lt"/"gte"/"lte"/"between" for the size/pushTime filters
+	Decoration string `json:"decoration,omitempty"`
+}
+
+// Artifact represents the artifact
+type Artifact struct {
+	Type string
+	// Digest is digest of the artifact
+	Digest string
+	// Tags are the tags of the artifact itself
+	Tags []string
+	// Labels are the labels of the artifact
+	Labels []string
+	// IsAcc indicates the artifact is an accessory of another artifact
+	IsAcc bool
+	// ParentTags are the tags of the artifact this one is an accessory of,
+	// used for matching when IsAcc is true
+	ParentTags []string
+	// Size is the size of the artifact in bytes, populated by adapters
+	// that can report it; zero when not supported by the source registry
+	Size int64
+	// PushTime is when the artifact was pushed, populated by adapters
+	// that can report it; the zero value when not supported by the
+	// source registry
+	PushTime time.Time
+}